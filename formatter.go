@@ -0,0 +1,283 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatter renders a Record into the bytes a LogWriter should receive.
+// Every sink picks its rendering independently via a Formatter; the
+// historical prefix()/reshape() rendering - the one preset everything used
+// to be stuck with - is just TerminalFormatter.
+type Formatter interface {
+	Format(r Record) ([]byte, error)
+}
+
+// DefaultPattern is used by WriterHandler when no Formatter is given: a
+// timestamp, a single-letter level, and the message with its fields.
+const DefaultPattern = "%T [%L] - %M"
+
+// RawFormatter renders only a Record's message and structured fields, with
+// no timestamp or level prefix of its own. It's for LogWriters that build
+// their own time/level envelope from the t/level arguments WriteMsg already
+// receives - SocketWriter's JSON record, SyslogWriter's severity-mapped
+// call - so that envelope isn't given an already-formatted string (with its
+// own duplicate timestamp and level baked in) as its message.
+type RawFormatter struct{}
+
+func (RawFormatter) Format(r Record) ([]byte, error) {
+	msg := r.Message
+	if kv := formatKV(r.KV); kv != "" {
+		msg += " " + kv
+	}
+	return []byte(msg), nil
+}
+
+// ioLogWriter adapts a plain io.Writer to LogWriter, for Formatters that
+// just need somewhere to put fully-rendered bytes and have no rotation or
+// dialing behaviour of their own (JSON/logfmt to a file, a pattern to
+// stdout, etc).
+type ioLogWriter struct {
+	w io.Writer
+}
+
+// NewIOLogWriter wraps w as a LogWriter. WriteMsg ignores t and level; if w
+// implements io.Closer, Close closes it.
+func NewIOLogWriter(w io.Writer) LogWriter {
+	return &ioLogWriter{w: w}
+}
+
+func (i *ioLogWriter) WriteMsg(t time.Time, level Level, msg string) error {
+	_, err := io.WriteString(i.w, msg)
+	return err
+}
+
+func (i *ioLogWriter) Close() error {
+	if c, ok := i.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// NewJSONHandler returns a Handler that writes each record as a single line
+// of JSON to w.
+func NewJSONHandler(w io.Writer, level Level) Handler {
+	return NewWriterHandler(NewIOLogWriter(w), level, JSONFormatter{})
+}
+
+// NewLogfmtHandler returns a Handler that writes each record as logfmt
+// (key=value) pairs to w.
+func NewLogfmtHandler(w io.Writer, level Level) Handler {
+	return NewWriterHandler(NewIOLogWriter(w), level, LogfmtFormatter{})
+}
+
+// JSONFormatter renders a Record as a single line of JSON, built on
+// log/slog.NewJSONHandler (see slog.go) rather than hand-rolled encoding,
+// per the original slog migration this module is built around.
+type JSONFormatter struct{}
+
+func (JSONFormatter) Format(r Record) ([]byte, error) {
+	return slogFormat(r, func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+		return slog.NewJSONHandler(w, opts)
+	})
+}
+
+// LogfmtFormatter renders a Record as logfmt (space-separated key=value)
+// pairs, built on log/slog.NewTextHandler (see slog.go) rather than
+// hand-rolled quoting.
+type LogfmtFormatter struct{}
+
+func (LogfmtFormatter) Format(r Record) ([]byte, error) {
+	return slogFormat(r, func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+		return slog.NewTextHandler(w, opts)
+	})
+}
+
+// PatternFormatter renders a Record according to a format string made of
+// literal text and tokens:
+//
+//	%T        RFC3339-ish timestamp
+//	%L        single-letter level (T/I/D/W/E)
+//	%M        message, followed by any structured fields
+//	%S        source "file:line" of the log call
+//	%P        process ID
+//	%N        logger name (see Logger.Named)
+//	%{key}    the structured field named key, or "" if absent
+//
+// Any verb except %{...} accepts a width and alignment modifier between the
+// '%' and the verb letter, e.g. "%-8L" left-aligns %L in 8 characters
+// (padding on the right) and "%8L" right-aligns it (padding on the left).
+type PatternFormatter struct {
+	tokens []patternToken
+}
+
+type patternToken struct {
+	literal   string
+	verb      byte // 0 means literal
+	key       string
+	width     int
+	leftAlign bool
+}
+
+// NewPatternFormatter compiles pattern once so Format doesn't re-parse it
+// on every record.
+func NewPatternFormatter(pattern string) *PatternFormatter {
+	return &PatternFormatter{tokens: parsePattern(pattern)}
+}
+
+func (p *PatternFormatter) Format(r Record) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, tok := range p.tokens {
+		if tok.verb == 0 {
+			buf.WriteString(tok.literal)
+			continue
+		}
+		buf.WriteString(pad(renderVerb(tok, r), tok.width, tok.leftAlign))
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func renderVerb(tok patternToken, r Record) string {
+	switch tok.verb {
+	case 'T':
+		return r.Time.Format("2006-01-02T15:04:05-0700")
+	case 'L':
+		return levelChar(r.Level)
+	case 'M':
+		msg := r.Message
+		if kv := formatKV(r.KV); kv != "" {
+			msg += " " + kv
+		}
+		return msg
+	case 'S':
+		return r.Source
+	case 'P':
+		return strconv.Itoa(os.Getpid())
+	case 'N':
+		return r.Logger
+	case '{':
+		return fmt.Sprintf("%v", lookupKV(r.KV, tok.key))
+	default:
+		return ""
+	}
+}
+
+// isPatternVerb reports whether verb is one of the recognized %-tokens
+// parsePattern and renderVerb handle. '{' is included for completeness,
+// though parsePattern handles %{key} before it consults this.
+func isPatternVerb(verb rune) bool {
+	switch verb {
+	case 'T', 'L', 'M', 'S', 'P', 'N', '{':
+		return true
+	default:
+		return false
+	}
+}
+
+func lookupKV(kv []any, key string) any {
+	for i := 0; i+1 < len(kv); i += 2 {
+		if k, ok := kv[i].(string); ok && k == key {
+			return kv[i+1]
+		}
+	}
+	return ""
+}
+
+func pad(s string, width int, leftAlign bool) string {
+	if width <= 0 || len(s) >= width {
+		return s
+	}
+	padding := strings.Repeat(" ", width-len(s))
+	if leftAlign {
+		return s + padding
+	}
+	return padding + s
+}
+
+// parsePattern splits pattern into a sequence of literal runs and tokens.
+func parsePattern(pattern string) []patternToken {
+	var (
+		tokens []patternToken
+		lit    bytes.Buffer
+		runes  = []rune(pattern)
+	)
+	flush := func() {
+		if lit.Len() > 0 {
+			tokens = append(tokens, patternToken{literal: lit.String()})
+			lit.Reset()
+		}
+	}
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		if c != '%' || i == len(runes)-1 {
+			lit.WriteRune(c)
+			i++
+			continue
+		}
+
+		j := i + 1
+		leftAlign := false
+		if runes[j] == '-' {
+			leftAlign = true
+			j++
+		}
+		widthStart := j
+		for j < len(runes) && runes[j] >= '0' && runes[j] <= '9' {
+			j++
+		}
+		if j >= len(runes) {
+			lit.WriteRune(c)
+			i++
+			continue
+		}
+		width := 0
+		if j > widthStart {
+			width, _ = strconv.Atoi(string(runes[widthStart:j]))
+		}
+
+		verb := runes[j]
+		if verb == '{' {
+			end := strings.IndexRune(string(runes[j+1:]), '}')
+			if end < 0 {
+				lit.WriteRune(c)
+				i++
+				continue
+			}
+			flush()
+			tokens = append(tokens, patternToken{
+				verb:      '{',
+				key:       string(runes[j+1 : j+1+end]),
+				width:     width,
+				leftAlign: leftAlign,
+			})
+			i = j + 1 + end + 1
+			continue
+		}
+
+		if !isPatternVerb(verb) {
+			// Not a recognized verb (and not the %{key} form handled above)
+			// - treat the leading '%' as a literal and let everything from
+			// here, including any width/alignment-looking characters we
+			// just scanned past, re-parse as ordinary text. Otherwise a
+			// stray '%' in a hand-authored pattern (e.g. "100% done")
+			// would silently swallow the next character instead of
+			// surviving unchanged.
+			lit.WriteRune(c)
+			i++
+			continue
+		}
+
+		flush()
+		tokens = append(tokens, patternToken{verb: byte(verb), width: width, leftAlign: leftAlign})
+		i = j + 1
+	}
+	flush()
+	return tokens
+}
@@ -0,0 +1,97 @@
+package log
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestPatternFormatterTokens checks the individual %T/%L/%M/%S/%P/%N/%{key}
+// tokens, including width/alignment modifiers and the {key} lookup for a
+// missing field.
+func TestPatternFormatterTokens(t *testing.T) {
+	r := Record{
+		Time:    time.Date(2026, 7, 25, 21, 0, 29, 0, time.UTC),
+		Level:   Warning,
+		Message: "hello",
+		Logger:  "sub",
+		Source:  "pkg/file.go:42",
+		KV:      []any{"req", "abc"},
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    string
+	}{
+		{"level", "%L", "W"},
+		{"message with kv", "%M", "hello req=abc"},
+		{"source", "%S", "pkg/file.go:42"},
+		{"pid", "%P", strconv.Itoa(os.Getpid())},
+		{"name", "%N", "sub"},
+		{"known key", "%{req}", "abc"},
+		{"missing key", "%{missing}", ""},
+		{"left align width", "[%-6L]", "[W     ]"},
+		{"right align width", "[%6L]", "[     W]"},
+		{"literal text", "lvl=%L msg=%M", "lvl=W msg=hello req=abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewPatternFormatter(tt.pattern)
+			got, err := f.Format(r)
+			if err != nil {
+				t.Fatalf("Format: %v", err)
+			}
+			// Format always appends a trailing newline; add it to want for
+			// the comparison since each test case above is a single line.
+			if string(got) != tt.want+"\n" {
+				t.Errorf("Format(%q) = %q, want %q", tt.pattern, got, tt.want+"\n")
+			}
+		})
+	}
+}
+
+// TestPatternFormatterTimeToken checks the %T token renders the same
+// layout prefix() uses, independent of the fixed fields covered above.
+func TestPatternFormatterTimeToken(t *testing.T) {
+	r := Record{Time: time.Date(2026, 7, 25, 21, 0, 29, 0, time.UTC), Level: Information}
+	f := NewPatternFormatter("%T")
+	got, err := f.Format(r)
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	want := r.Time.Format("2006-01-02T15:04:05-0700") + "\n"
+	if string(got) != want {
+		t.Errorf("Format(%%T) = %q, want %q", got, want)
+	}
+}
+
+// TestPatternFormatterUnrecognizedPercent checks that a '%' not forming a
+// recognized token - whether trailing with nothing to consume, or followed
+// by an unrecognized verb letter, with or without a width/alignment
+// modifier - is reproduced as literal text instead of being swallowed along
+// with whatever followed it.
+func TestPatternFormatterUnrecognizedPercent(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"100% done", "100% done"},
+		{"%Z unknown verb", "%Z unknown verb"},
+		{"trailing percent %", "trailing percent %"},
+		{"%-8Z unknown with width", "%-8Z unknown with width"},
+	}
+	r := Record{Message: "msg"}
+	for _, tt := range tests {
+		f := NewPatternFormatter(tt.pattern)
+		got, err := f.Format(r)
+		if err != nil {
+			t.Fatalf("Format(%q): %v", tt.pattern, err)
+		}
+		if string(got) != tt.want+"\n" {
+			t.Errorf("Format(%q) = %q, want %q", tt.pattern, got, tt.want+"\n")
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package log
+
+import "time"
+
+// Record is a single log event as passed from a Logger to a Handler. It
+// carries everything a Handler needs to format and emit the event without
+// reaching back into the Logger that produced it.
+type Record struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	// Logger is the name of the Logger that produced the record, if any.
+	Logger string
+	// Source is the "file:line" of the caller, captured with runtime.Caller.
+	Source string
+	// KV holds the record's structured fields as alternating key/value pairs,
+	// including any persistent fields attached via Logger.With.
+	KV []any
+}
+
+// Handler decides whether a Record should be emitted and, if so, formats and
+// writes it somewhere. Loggers are thin wrappers around a Handler; all of the
+// actual formatting/output behaviour lives here so it can be swapped out.
+type Handler interface {
+	// Enabled reports whether the handler wants records at the given level.
+	// It lets a Logger skip building a Record at all for a disabled level.
+	Enabled(level Level) bool
+	// Handle emits the record. Handlers are called synchronously and in the
+	// order they were composed, so a slow Handle blocks its siblings.
+	Handle(r Record) error
+}
+
+// MultiHandler fans a Record out to every handler it wraps, mirroring the
+// old Logger.loggers []io.Writer behaviour but at the Handler level.
+type MultiHandler struct {
+	handlers []Handler
+}
+
+// NewMultiHandler composes the given handlers into one. A nil entry is
+// skipped so callers can build the slice conditionally without filtering it
+// themselves.
+func NewMultiHandler(handlers ...Handler) *MultiHandler {
+	m := &MultiHandler{}
+	for _, h := range handlers {
+		if h != nil {
+			m.handlers = append(m.handlers, h)
+		}
+	}
+	return m
+}
+
+// Enabled reports true if any of the wrapped handlers would handle level.
+func (m *MultiHandler) Enabled(level Level) bool {
+	for _, h := range m.handlers {
+		if h.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle passes r to every wrapped handler, continuing past individual
+// errors so one broken sink (e.g. a closed socket) doesn't silence the rest.
+func (m *MultiHandler) Handle(r Record) error {
+	var firstErr error
+	for _, h := range m.handlers {
+		if !h.Enabled(r.Level) {
+			continue
+		}
+		if err := h.Handle(r); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// mergeKV concatenates a logger's persistent fields with the fields passed to
+// an individual log call, without mutating either slice.
+func mergeKV(base, extra []any) []any {
+	if len(base) == 0 {
+		return extra
+	}
+	if len(extra) == 0 {
+		return base
+	}
+	out := make([]any, 0, len(base)+len(extra))
+	out = append(out, base...)
+	out = append(out, extra...)
+	return out
+}
@@ -1,14 +1,10 @@
 package log
 
 import (
-	"bytes"
 	"fmt"
-	"io"
 	"os"
-	"regexp"
 	"runtime"
-
-	"log"
+	"sync/atomic"
 	"time"
 )
 
@@ -21,9 +17,17 @@ const (
 	DefaultLogOptions = LogToStdout | LogToFile | ReshapeLogs
 )
 
-var level Level = TraceL
+// level is the package-wide verbosity threshold applied by globalLevelHandler.
+// It's read on every record, so it's kept in an atomic rather than behind a
+// mutex - readers never block, and SetLogLevel never blocks a logger
+// mid-flight.
+var level atomic.Int32
+
+func init() {
+	level.Store(int32(TraceL))
+}
 
-func any(lhs string, rhs []string) bool {
+func matchesAny(lhs string, rhs []string) bool {
 	for _, item := range rhs {
 		if item == lhs {
 			return true
@@ -35,72 +39,71 @@ func any(lhs string, rhs []string) bool {
 // SetLogLevel tries to parse the given string to figure out the desired log
 // level for the application
 func SetLogLevel(l string) {
-	if any(l, []string{"information", "info", "i", "in"}) {
-		level = Information
-	} else if any(l, []string{"warning", "warn", "w", "wa"}) {
-		level = Warning
-	} else if any(l, []string{"error", "err", "er", "e"}) {
-		level = ErrorL
-	} else if any(l, []string{"debug", "deb", "de", "d"}) {
-		level = DebugL
+	var lev Level
+	if matchesAny(l, []string{"information", "info", "i", "in"}) {
+		lev = Information
+	} else if matchesAny(l, []string{"warning", "warn", "w", "wa"}) {
+		lev = Warning
+	} else if matchesAny(l, []string{"error", "err", "er", "e"}) {
+		lev = ErrorL
+	} else if matchesAny(l, []string{"debug", "deb", "de", "d"}) {
+		lev = DebugL
 	} else {
-		level = TraceL
+		lev = TraceL
 	}
+	level.Store(int32(lev))
 }
 
-// file is essentially a wrapper to satisfy the io.Writer interface by using
-// Write to handle file opening and closing operations
+// file adapts a RotatingFileWriter to the io.Writer interface TerminalHandler
+// expects, so the stdout and file sinks built by New can share the same
+// formatting path. It keeps a single open file handle across writes instead
+// of the historical open-write-close on every message.
 type file struct {
-	Name string
+	rw *RotatingFileWriter
 }
 
-type Logger struct {
-	loggers []io.Writer
-	options LogOptions
+func newFile(name string) (*file, error) {
+	rw, err := NewRotatingFileWriter(name, 0, false, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	return &file{rw: rw}, nil
 }
 
 func (f *file) Write(p []byte) (n int, err error) {
-	n = len(p)
-	return n, f.WriteMsg(string(p))
+	if err := f.rw.WriteMsg(time.Now(), Information, string(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
 }
 
-// WriteMsg is the internal wrapper for the interface satisfying of the logging
-// functionality
-func (f *file) WriteMsg(msg string, args ...interface{}) error {
-	perms := os.O_APPEND | os.O_WRONLY | os.O_CREATE
-	file, err := os.OpenFile(f.Name, perms, os.ModeAppend)
-	defer file.Close()
-	if err == nil {
-		if _, err := fmt.Fprintf(file, msg+"\n", args...); err != nil {
-			fmt.Fprintln(os.Stdout, err.Error())
-		} else {
-			return nil
-		}
-	} else if os.IsNotExist(err) {
-		re := regexp.MustCompile("[A-Za-z0-9." + dirDelimit + "]+" + dirDelimit)
-		dirPath := re.FindString(f.Name)
-		if err = os.MkdirAll(dirPath, 0744); err == nil {
-			file, err = os.OpenFile(f.Name, perms, os.ModeAppend)
-		}
-		if os.IsExist(err) {
-			panic(err)
-		} else {
-			errMsg := fmt.Sprintf("Could not open log file: %v", err)
-			panic(errMsg)
-		}
-	} else {
-		err = fmt.Errorf("Could not log to file: %v", err)
-		fmt.Fprintln(os.Stdout, err.Error())
-		return err
-	}
-	// From line 39
-	if err != nil {
-		fmt.Fprintln(os.Stdout, err.Error())
-	}
-	if _, err := fmt.Fprintf(file, msg+"\n", args...); err != nil {
-		fmt.Fprintln(os.Stdout, err.Error())
+func (f *file) Close() error {
+	return f.rw.Close()
+}
+
+// Logger is a thin handle around a Handler: it carries the persistent
+// key/value fields attached with With and a caller-friendly method surface,
+// but all formatting and output is delegated to the Handler. The handler is
+// held behind an atomic.Pointer so SetHandler can hot-swap it (e.g. to add a
+// sink at runtime) without a lock on the hot Trace/Info/... path.
+type Logger struct {
+	handler atomic.Pointer[Handler]
+	kv      []any
+	name    string
+}
+
+func (l *Logger) getHandler() Handler {
+	h := l.handler.Load()
+	if h == nil {
+		return nil
 	}
-	return nil
+	return *h
+}
+
+// SetHandler replaces the Logger's Handler in place, visible to any
+// in-flight or future log calls without taking a lock.
+func (l *Logger) SetHandler(h Handler) {
+	l.handler.Store(&h)
 }
 
 // Level of the desired log
@@ -126,142 +129,154 @@ func NewDefault(logFile string) *Logger {
 	return New(logFile, DefaultLogOptions)
 }
 
-// New returns a new instance of a logger object on demand
+// New returns a new instance of a logger object on demand, wired up with the
+// historical stdout/file Handler combination.
 func New(logFile string, options LogOptions) *Logger {
-	l := Logger{}
+	var handlers []Handler
 	// Changed because docker-compose logs are really useful
 	if runtime.GOOS != "windows" || options&LogToStdout > 0 {
-		l.loggers = append(l.loggers, os.Stdout)
+		handlers = append(handlers, NewTerminalHandler(os.Stdout, TraceL, options&ReshapeLogs > 0, true))
 	}
 	if options&LogToFile > 0 {
-		f := &file{
-			Name: logFile,
+		f, err := newFile(logFile)
+		if err != nil {
+			panic(fmt.Sprintf("Could not open log file: %v", err))
 		}
-		l.loggers = append(l.loggers, io.Writer(f))
+		handlers = append(handlers, NewTerminalHandler(f, TraceL, options&ReshapeLogs > 0, false))
 	}
-	l.options = options
-	return &l
+	return NewWithHandler(newGlobalLevelHandler(NewMultiHandler(handlers...)))
 }
 
-func prefix(level Level) string {
-	str := time.Now().Format("2006-01-02T15:04:05-0700")
-	char := ""
-	switch level {
-	case TraceL:
-		char = "T"
-	case Information:
-		char = "I"
-	case Warning:
-		char = "W"
-	case DebugL:
-		char = "D"
-	default:
-		char = "E"
+// NewFromWriters returns a Logger fanning out to the given LogWriters - e.g.
+// a SyslogWriter and a RotatingFileWriter - in addition to the stdout
+// handler New would build, letting callers compose sinks beyond the
+// stdout/file pair without hand-rolling a MultiHandler.
+func NewFromWriters(writers []LogWriter, options LogOptions) *Logger {
+	var handlers []Handler
+	if runtime.GOOS != "windows" || options&LogToStdout > 0 {
+		handlers = append(handlers, NewTerminalHandler(os.Stdout, TraceL, options&ReshapeLogs > 0, true))
+	}
+	for _, w := range writers {
+		handlers = append(handlers, NewWriterHandler(w, TraceL, nil))
 	}
-	return fmt.Sprintf("%v [%v] -\t", str, char)
+	return NewWithHandler(newGlobalLevelHandler(NewMultiHandler(handlers...)))
 }
 
-func (l *Logger) _log(lev Level, format string, args ...interface{}) {
-	if len(l.loggers) == 0 {
-		panic("Could not log because no loggers are configured")
-	}
-	if lev < level {
-		return
-	}
-	log.SetOutput(io.MultiWriter(l.loggers...))
-	var msg string
-	fmsg := fmt.Sprintf(format, args...)
-	if l.options&ReshapeLogs > 0 {
-		msg = reshape(prefix(lev), fmsg)
-	} else {
-		msg = prefix(lev) + fmsg
-	}
-	for _, logger := range l.loggers {
-		fmt.Fprintln(logger, msg)
-	}
+// globalLevelHandler gates records against the package-wide SetLogLevel
+// threshold before delegating to next, the same way TerminalHandler and
+// WriterHandler gate against their own fixed level. New and NewFromWriters
+// wrap their default handlers in one so SetLogLevel keeps working for those
+// convenience constructors; a caller-supplied Handler passed to
+// NewWithHandler - a VmoduleHandler's per-package thresholds, say - isn't
+// wrapped, so its own enablement decision is authoritative instead of being
+// pre-empted by the unrelated global threshold.
+type globalLevelHandler struct {
+	next Handler
 }
 
-// reshape attempts to answer the visual problem of giving a margin to text
-// based on the length of the desired prefix. This is so tha the eye level of
-// the logs are aligned without having to worry about having to sort through
-// the. Assumes ASCII
-func reshape(prefix, text string) string {
-	leftmargin := len(prefix)
-	var (
-		words = make([][]byte, 0, len(text))
-		_text = []byte(text)
-		word  = make([]byte, 0, 15)
-		buf   bytes.Buffer
-	)
-	for i, char := range _text {
-		if char == 0x20 || char == 0xA || char == 0xD {
-			if len(word) > 0 {
-				words = append(words, word)
-			}
-			word = make([]byte, 0, 15)
-			continue
-		}
-		word = append(word, char)
-		if i == len(_text)-1 {
-			words = append(words, word)
-		}
+func newGlobalLevelHandler(next Handler) *globalLevelHandler {
+	return &globalLevelHandler{next: next}
+}
+
+func (g *globalLevelHandler) Enabled(lvl Level) bool {
+	return lvl >= Level(level.Load()) && g.next.Enabled(lvl)
+}
+
+func (g *globalLevelHandler) Handle(r Record) error {
+	if r.Level < Level(level.Load()) {
+		return nil
 	}
+	return g.next.Handle(r)
+}
 
-	// Will likely not grow very often, so safe to give a small header
-	buf.Grow(len(text) + 50)
+// NewWithHandler returns a Logger backed by a caller-supplied Handler, for
+// callers that want something other than the stdout/file combination New
+// builds - a JSON handler for a sidecar collector, a VmoduleHandler for
+// per-package verbosity, or a MultiHandler composing several of these.
+func NewWithHandler(h Handler) *Logger {
+	l := &Logger{}
+	l.SetHandler(h)
+	return l
+}
 
-	line := make([]byte, 0, 15)
-	initLine := func(linesIndex int) {
-		line = make([]byte, 0, 15)
-		if linesIndex == 0 {
-			return
-		}
-		for i := 0; i < leftmargin-4; i++ {
-			line = append(line, 0x20)
-		}
-		line = append(line, 0x9)
+// With returns a copy of the Logger that attaches kv to every subsequent log
+// call, in addition to anything already attached by an earlier With. The
+// copy starts out with the same Handler but holds it separately, so a later
+// SetHandler on one Logger does not affect the other.
+func (l *Logger) With(kv ...any) *Logger {
+	nl := &Logger{kv: mergeKV(l.kv, kv), name: l.name}
+	nl.SetHandler(l.getHandler())
+	return nl
+}
+
+// Named returns a copy of the Logger tagged with name, available to a
+// Formatter via the %N pattern token or a JSONFormatter/LogfmtFormatter's
+// "logger" field.
+func (l *Logger) Named(name string) *Logger {
+	nl := &Logger{kv: l.kv, name: name}
+	nl.SetHandler(l.getHandler())
+	return nl
+}
+
+func (l *Logger) log(lev Level, msg string, kv ...any) {
+	// Frames above logAt: log, then Trace/Info/..., then the real call site.
+	l.logAt(lev, msg, 3, kv...)
+}
+
+// logAt is log with an explicit runtime.Caller skip count, for callers that
+// aren't a direct Trace/Debug/Info/Warn/Error wrapper two frames up from the
+// real call site - notably SubprocessLogger.emit, which relays a line that
+// didn't originate from this call stack at all, so attaching emit's own
+// location as Source would just be misleading. skip <= 0 means "don't
+// attempt to capture a Source".
+func (l *Logger) logAt(lev Level, msg string, skip int, kv ...any) {
+	h := l.getHandler()
+	if h == nil {
+		panic("Could not log because no handler is configured")
 	}
-	initLine(0)
-	line = []byte(prefix)
-	for i, word := range words {
-		if len(word)+len(line) > TextMaxWidth {
-			buf.Write(line)
-			buf.WriteString(carriageReturn)
-			initLine(i)
-		}
-		if len(line) > 0 {
-			line = append(line, 0x20)
-		}
-		line = append(line, word...)
-		if i == len(words)-1 {
-			buf.Write(line)
+	if !h.Enabled(lev) {
+		return
+	}
+	src := ""
+	if skip > 0 {
+		if _, f, ln, ok := runtime.Caller(skip); ok {
+			src = fmt.Sprintf("%s:%d", f, ln)
 		}
 	}
-
-	return buf.String()
+	r := Record{
+		Time:    time.Now(),
+		Level:   lev,
+		Message: msg,
+		Logger:  l.name,
+		Source:  src,
+		KV:      mergeKV(l.kv, kv),
+	}
+	if err := h.Handle(r); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+	}
 }
 
-// Trace issues a log with trace level
-func (l *Logger) Trace(fmt string, args ...interface{}) {
-	l._log(TraceL, fmt, args...)
+// Trace issues a log with trace level, attaching kv as structured fields.
+func (l *Logger) Trace(msg string, kv ...any) {
+	l.log(TraceL, msg, kv...)
 }
 
-// Warn issues a log as a warning
-func (l *Logger) Warn(fmt string, args ...interface{}) {
-	l._log(Warning, fmt, args...)
+// Warn issues a log as a warning, attaching kv as structured fields.
+func (l *Logger) Warn(msg string, kv ...any) {
+	l.log(Warning, msg, kv...)
 }
 
-// Info issues a log as information
-func (l *Logger) Info(fmt string, args ...interface{}) {
-	l._log(Information, fmt, args...)
+// Info issues a log as information, attaching kv as structured fields.
+func (l *Logger) Info(msg string, kv ...any) {
+	l.log(Information, msg, kv...)
 }
 
-// Debug issues a log as debug information
-func (l *Logger) Debug(fmt string, args ...interface{}) {
-	l._log(DebugL, fmt, args...)
+// Debug issues a log as debug information, attaching kv as structured fields.
+func (l *Logger) Debug(msg string, kv ...any) {
+	l.log(DebugL, msg, kv...)
 }
 
-// Error issues a log as an error message
-func (l *Logger) Error(fmt string, args ...interface{}) {
-	l._log(ErrorL, fmt, args...)
+// Error issues a log as an error message, attaching kv as structured fields.
+func (l *Logger) Error(msg string, kv ...any) {
+	l.log(ErrorL, msg, kv...)
 }
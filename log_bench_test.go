@@ -0,0 +1,33 @@
+package log
+
+import (
+	"io"
+	"testing"
+)
+
+// BenchmarkLoggerInfoConcurrent exercises Info from many goroutines at once
+// through the default terminal handler, to track contention on the hot
+// path (handler/level reads, buffer formatting, and the final Write).
+func BenchmarkLoggerInfoConcurrent(b *testing.B) {
+	logger := NewWithHandler(NewTerminalHandler(io.Discard, TraceL, true, false))
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info("benchmark message", "worker", "bench", "iteration", 1)
+		}
+	})
+}
+
+// BenchmarkLoggerInfoConcurrentWith is like BenchmarkLoggerInfoConcurrent but
+// through a Logger derived with With, to cover the persistent-kv merge path.
+func BenchmarkLoggerInfoConcurrentWith(b *testing.B) {
+	logger := NewWithHandler(NewTerminalHandler(io.Discard, TraceL, true, false)).With("component", "bench")
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info("benchmark message", "iteration", 1)
+		}
+	})
+}
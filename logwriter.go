@@ -0,0 +1,55 @@
+package log
+
+import "time"
+
+// LogWriter is a sink for fully-rendered log lines. Unlike a bare io.Writer,
+// it receives the record's time and level directly, which lets
+// implementations map them onto their own model - syslog severities, a JSON
+// envelope field, rotation bookkeeping - instead of having to re-parse a
+// formatted string.
+type LogWriter interface {
+	WriteMsg(t time.Time, level Level, msg string) error
+	Close() error
+}
+
+// WriterHandler is the Handler side of a LogWriter: it renders a Record
+// through a Formatter, then hands the result to the LogWriter instead of an
+// io.Writer. Each WriterHandler owns its own Formatter, so one LogWriter can
+// get JSON while another gets a plain pattern.
+type WriterHandler struct {
+	w         LogWriter
+	level     Level
+	formatter Formatter
+}
+
+// NewWriterHandler returns a Handler that emits through w using formatter.
+// level is the handler's own threshold, independent of the package-wide
+// SetLogLevel threshold applied by globalLevelHandler. A nil formatter
+// falls back to DefaultPattern.
+func NewWriterHandler(w LogWriter, level Level, formatter Formatter) *WriterHandler {
+	if formatter == nil {
+		formatter = NewPatternFormatter(DefaultPattern)
+	}
+	return &WriterHandler{w: w, level: level, formatter: formatter}
+}
+
+func (h *WriterHandler) Enabled(level Level) bool {
+	return level >= h.level
+}
+
+func (h *WriterHandler) Handle(r Record) error {
+	b, err := h.formatter.Format(r)
+	if err != nil {
+		return err
+	}
+	return h.w.WriteMsg(r.Time, r.Level, string(b))
+}
+
+// NewSyslogHandler returns a Handler emitting through w, forcing a
+// RawFormatter regardless of what's passed elsewhere - the syslog daemon
+// already timestamps and severity-tags every message, so msg must hold only
+// the unprefixed message text, not a second copy of the same time/level
+// rendered by some other Formatter.
+func NewSyslogHandler(w LogWriter, level Level) *WriterHandler {
+	return NewWriterHandler(w, level, RawFormatter{})
+}
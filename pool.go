@@ -0,0 +1,30 @@
+package log
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBufSize bounds how large a *bytes.Buffer we'll hand back to the
+// pool. Without this, one abnormally long line grows its buffer's backing
+// array and that oversized array then sits in the pool indefinitely,
+// inflating memory for every goroutine that happens to draw it next.
+const maxPooledBufSize = 64 * 1024
+
+var bufPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// getBuf returns an empty buffer ready for formatting a single record.
+func getBuf() *bytes.Buffer {
+	return bufPool.Get().(*bytes.Buffer)
+}
+
+// putBuf returns buf to the pool, unless it grew past maxPooledBufSize.
+func putBuf(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufSize {
+		return
+	}
+	buf.Reset()
+	bufPool.Put(buf)
+}
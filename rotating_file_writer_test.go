@@ -0,0 +1,129 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRotatingFileWriterRotatesAtSize checks that once the file has reached
+// maxSize, the next write rotates it to a timestamped backup before that
+// write lands in a fresh file.
+func TestRotatingFileWriterRotatesAtSize(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewRotatingFileWriter(name, 5, false, 0, false)
+	if err != nil {
+		t.Fatalf("NewRotatingFileWriter: %v", err)
+	}
+	defer w.Close()
+
+	now := time.Now()
+	if err := w.WriteMsg(now, Information, "first"); err != nil {
+		t.Fatalf("WriteMsg: %v", err)
+	}
+	if err := w.WriteMsg(now, Information, "second"); err != nil {
+		t.Fatalf("WriteMsg: %v", err)
+	}
+
+	matches, err := filepath.Glob(name + ".*")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("got %d backups, want 1 (matches=%v)", len(matches), matches)
+	}
+
+	got, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if strings.TrimSpace(string(got)) != "second" {
+		t.Errorf("current file = %q, want %q", got, "second\n")
+	}
+}
+
+// TestRotatingFileWriterRotatesDaily checks that a write on a new day
+// rotates the file even though maxSize hasn't been crossed.
+func TestRotatingFileWriterRotatesDaily(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewRotatingFileWriter(name, 0, true, 0, false)
+	if err != nil {
+		t.Fatalf("NewRotatingFileWriter: %v", err)
+	}
+	defer w.Close()
+
+	today := time.Now()
+	if err := w.WriteMsg(today, Information, "day one"); err != nil {
+		t.Fatalf("WriteMsg: %v", err)
+	}
+
+	tomorrow := today.Add(24 * time.Hour)
+	if err := w.WriteMsg(tomorrow, Information, "day two"); err != nil {
+		t.Fatalf("WriteMsg: %v", err)
+	}
+
+	matches, err := filepath.Glob(name + ".*")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("got %d backups, want 1 (matches=%v)", len(matches), matches)
+	}
+}
+
+// TestRotatingFileWriterPrunesBackups checks that only the newest
+// maxBackups rotated segments survive repeated rotation.
+func TestRotatingFileWriterPrunesBackups(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewRotatingFileWriter(name, 1, false, 2, false)
+	if err != nil {
+		t.Fatalf("NewRotatingFileWriter: %v", err)
+	}
+	defer w.Close()
+
+	for i := 0; i < 4; i++ {
+		if err := w.WriteMsg(time.Now(), Information, "line"); err != nil {
+			t.Fatalf("WriteMsg %d: %v", i, err)
+		}
+	}
+
+	matches, err := filepath.Glob(name + ".*")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("got %d backups, want 2 (matches=%v)", len(matches), matches)
+	}
+}
+
+// TestRotatingFileWriterSameSecondRotationsDontCollide checks that several
+// rotations landing within the same wall-clock second - easy to hit with a
+// small maxSize under load - each get a distinct backup name instead of
+// os.Rename silently overwriting the previous one.
+func TestRotatingFileWriterSameSecondRotationsDontCollide(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewRotatingFileWriter(name, 1, false, 0, false)
+	if err != nil {
+		t.Fatalf("NewRotatingFileWriter: %v", err)
+	}
+	defer w.Close()
+
+	const writes = 20
+	for i := 0; i < writes; i++ {
+		if err := w.WriteMsg(time.Now(), Information, "line"); err != nil {
+			t.Fatalf("WriteMsg %d: %v", i, err)
+		}
+	}
+
+	matches, err := filepath.Glob(name + ".*")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	// The first write never rotates (nothing to rotate yet); every write
+	// after that does, since maxSize is crossed immediately.
+	if want := writes - 1; len(matches) != want {
+		t.Fatalf("got %d backups, want %d (matches=%v)", len(matches), want, matches)
+	}
+}
@@ -0,0 +1,85 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// slogLevel maps our Level (where higher is more severe but Debug/Info are
+// transposed for historical reasons, see Level) onto slog's, so
+// JSONFormatter/LogfmtFormatter can hand records to slog's own encoders
+// instead of re-implementing JSON/logfmt escaping.
+func (lev Level) slogLevel() slog.Level {
+	switch lev {
+	case TraceL:
+		return slog.LevelDebug - 4
+	case DebugL:
+		return slog.LevelDebug
+	case Information:
+		return slog.LevelInfo
+	case Warning:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// kvToAttrs turns an alternating key/value slice, as accepted by
+// Logger.Info and friends, into slog.Attr values. An odd trailing key is
+// kept with a "!BADKEY" marker, matching slog's own convention.
+func kvToAttrs(kv []any) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(kv)/2+1)
+	for i := 0; i < len(kv); i += 2 {
+		if i+1 >= len(kv) {
+			attrs = append(attrs, slog.Any("!BADKEY", kv[i]))
+			break
+		}
+		key, ok := kv[i].(string)
+		if !ok {
+			key = fmt.Sprintf("%v", kv[i])
+		}
+		attrs = append(attrs, slog.Any(key, kv[i+1]))
+	}
+	return attrs
+}
+
+// slogFormat renders r through an slog.Handler built by newHandler - JSON or
+// text/logfmt - so the actual field encoding and escaping is slog's, not
+// ours. The only thing we override via ReplaceAttr is slog's own level
+// rendering, swapped for our single-letter levelChar.
+func slogFormat(r Record, newHandler func(io.Writer, *slog.HandlerOptions) slog.Handler) ([]byte, error) {
+	buf := getBuf()
+	defer putBuf(buf)
+
+	opts := &slog.HandlerOptions{
+		// Our TraceL sits below slog.LevelDebug, so the handler must accept
+		// it rather than filtering it out - level gating already happened
+		// in WriterHandler/TerminalHandler before a Formatter is reached.
+		Level: slog.LevelDebug - 8,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.LevelKey {
+				a.Value = slog.StringValue(levelChar(r.Level))
+			}
+			return a
+		},
+	}
+	h := newHandler(buf, opts)
+
+	rec := slog.NewRecord(r.Time, r.Level.slogLevel(), r.Message, 0)
+	if r.Logger != "" {
+		rec.AddAttrs(slog.String("logger", r.Logger))
+	}
+	if r.Source != "" {
+		rec.AddAttrs(slog.String("source", r.Source))
+	}
+	rec.AddAttrs(kvToAttrs(r.KV)...)
+
+	if err := h.Handle(context.Background(), rec); err != nil {
+		return nil, err
+	}
+	out := make([]byte, buf.Len())
+	copy(out, buf.Bytes())
+	return out, nil
+}
@@ -0,0 +1,70 @@
+package log
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestJSONFormatterUsesSlogEncoding checks that JSONFormatter produces
+// valid JSON with our single-letter level substituted for slog's own, and
+// that logger/source/kv are all present as top-level fields.
+func TestJSONFormatterUsesSlogEncoding(t *testing.T) {
+	r := Record{
+		Time:    time.Date(2026, 7, 25, 21, 0, 29, 0, time.UTC),
+		Level:   Warning,
+		Message: "hello",
+		Logger:  "sub",
+		Source:  "pkg/file.go:42",
+		KV:      []any{"req", "abc"},
+	}
+
+	b, err := JSONFormatter{}.Format(r)
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal %q: %v", b, err)
+	}
+	if got["msg"] != "hello" {
+		t.Errorf("msg = %v, want %q", got["msg"], "hello")
+	}
+	if got["level"] != "W" {
+		t.Errorf("level = %v, want %q", got["level"], "W")
+	}
+	if got["logger"] != "sub" {
+		t.Errorf("logger = %v, want %q", got["logger"], "sub")
+	}
+	if got["source"] != "pkg/file.go:42" {
+		t.Errorf("source = %v, want %q", got["source"], "pkg/file.go:42")
+	}
+	if got["req"] != "abc" {
+		t.Errorf("req = %v, want %q", got["req"], "abc")
+	}
+}
+
+// TestLogfmtFormatterUsesSlogEncoding checks that LogfmtFormatter produces
+// key=value pairs via slog's text encoder, with our single-letter level.
+func TestLogfmtFormatterUsesSlogEncoding(t *testing.T) {
+	r := Record{
+		Time:    time.Date(2026, 7, 25, 21, 0, 29, 0, time.UTC),
+		Level:   ErrorL,
+		Message: "boom",
+		KV:      []any{"req", "abc"},
+	}
+
+	b, err := LogfmtFormatter{}.Format(r)
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	line := string(b)
+
+	for _, want := range []string{"msg=boom", "level=E", "req=abc"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("line %q missing %q", line, want)
+		}
+	}
+}
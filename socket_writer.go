@@ -0,0 +1,127 @@
+package log
+
+import (
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+)
+
+// SocketProtocol selects the transport a SocketWriter dials.
+type SocketProtocol string
+
+const (
+	TCP SocketProtocol = "tcp"
+	UDP SocketProtocol = "udp"
+)
+
+// socketRecord is the wire format written by SocketWriter, one JSON object
+// per line.
+type socketRecord struct {
+	Time  time.Time `json:"time"`
+	Level string    `json:"level"`
+	Msg   string    `json:"msg"`
+}
+
+// SocketWriter is a LogWriter that ships each record as a line-delimited
+// JSON object over a TCP or UDP connection. By default it keeps one
+// connection open and reconnects only after a write error; set
+// reconnectEveryMessage to dial fresh for every message instead, which costs
+// latency but tolerates a collector that resets connections aggressively.
+type SocketWriter struct {
+	protocol              SocketProtocol
+	addr                  string
+	reconnectEveryMessage bool
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSocketWriter dials addr over protocol and returns a ready SocketWriter.
+// If reconnectEveryMessage is true, the initial dial is skipped and a new
+// connection is made for every WriteMsg instead.
+func NewSocketWriter(protocol SocketProtocol, addr string, reconnectEveryMessage bool) (*SocketWriter, error) {
+	w := &SocketWriter{protocol: protocol, addr: addr, reconnectEveryMessage: reconnectEveryMessage}
+	if !reconnectEveryMessage {
+		if err := w.dial(); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+func (w *SocketWriter) dial() error {
+	conn, err := net.Dial(string(w.protocol), w.addr)
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+	return nil
+}
+
+// WriteMsg encodes the record as JSON and writes it, reconnecting as
+// configured by reconnectEveryMessage. With a persistent connection, a write
+// error is retried once on a fresh connection; with reconnectEveryMessage it
+// is returned without a retry.
+func (w *SocketWriter) WriteMsg(t time.Time, level Level, msg string) error {
+	payload, err := json.Marshal(socketRecord{Time: t, Level: levelChar(level), Msg: msg})
+	if err != nil {
+		return err
+	}
+	payload = append(payload, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.reconnectEveryMessage {
+		if err := w.dial(); err != nil {
+			return err
+		}
+		defer func() {
+			w.conn.Close()
+			w.conn = nil
+		}()
+	} else if w.conn == nil {
+		if err := w.dial(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.conn.Write(payload); err != nil {
+		if w.reconnectEveryMessage {
+			return err
+		}
+		// reconnect-on-error: the connection is dead, retry once on a fresh one.
+		w.conn.Close()
+		if derr := w.dial(); derr != nil {
+			return err
+		}
+		if _, err := w.conn.Write(payload); err != nil {
+			w.conn.Close()
+			w.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+// NewSocketHandler returns a Handler emitting through w, forcing a
+// RawFormatter regardless of what's passed elsewhere - SocketWriter.WriteMsg
+// builds its own {time, level, msg} envelope from the record's time and
+// level, so msg must hold only the unprefixed message text, not a second
+// copy of the same time/level already baked in by some other Formatter.
+func NewSocketHandler(w *SocketWriter, level Level) *WriterHandler {
+	return NewWriterHandler(w, level, RawFormatter{})
+}
+
+// Close releases the underlying connection, if any is currently held open.
+func (w *SocketWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
@@ -0,0 +1,110 @@
+package log
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+)
+
+// TestSocketWriterWritesBareEnvelope checks that SocketWriter's JSON
+// envelope holds exactly the time/level/msg it was given - not a second,
+// already time/level-prefixed copy of the message, which is what NewWriterHandler
+// would produce if given anything but a RawFormatter (see NewSocketHandler).
+func TestSocketWriterWritesBareEnvelope(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer ln.Close()
+
+	lines := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		lines <- line
+	}()
+
+	w, err := NewSocketWriter(TCP, ln.Addr().String(), false)
+	if err != nil {
+		t.Fatalf("NewSocketWriter: %v", err)
+	}
+	defer w.Close()
+
+	now := time.Date(2026, 7, 25, 21, 0, 29, 0, time.UTC)
+	if err := w.WriteMsg(now, Information, "hello world"); err != nil {
+		t.Fatalf("WriteMsg: %v", err)
+	}
+
+	var line string
+	select {
+	case line = <-lines:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for socket write")
+	}
+
+	var rec socketRecord
+	if err := json.Unmarshal([]byte(line), &rec); err != nil {
+		t.Fatalf("Unmarshal %q: %v", line, err)
+	}
+	if rec.Msg != "hello world" {
+		t.Errorf("Msg = %q, want %q", rec.Msg, "hello world")
+	}
+	if rec.Level != "I" {
+		t.Errorf("Level = %q, want %q", rec.Level, "I")
+	}
+	if !rec.Time.Equal(now) {
+		t.Errorf("Time = %v, want %v", rec.Time, now)
+	}
+}
+
+// TestSocketHandlerDoesNotDoubleEncode checks that a Logger logging through
+// NewSocketHandler produces a wire message with msg holding only the
+// unprefixed text, even though the Record carries structured fields.
+func TestSocketHandlerDoesNotDoubleEncode(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer ln.Close()
+
+	lines := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		lines <- line
+	}()
+
+	sw, err := NewSocketWriter(TCP, ln.Addr().String(), false)
+	if err != nil {
+		t.Fatalf("NewSocketWriter: %v", err)
+	}
+	defer sw.Close()
+
+	logger := NewWithHandler(NewSocketHandler(sw, TraceL))
+	logger.Info("hello world", "key", "val")
+
+	var line string
+	select {
+	case line = <-lines:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for socket write")
+	}
+
+	var rec socketRecord
+	if err := json.Unmarshal([]byte(line), &rec); err != nil {
+		t.Fatalf("Unmarshal %q: %v", line, err)
+	}
+	if rec.Msg != "hello world key=val" {
+		t.Errorf("Msg = %q, want %q", rec.Msg, "hello world key=val")
+	}
+}
@@ -0,0 +1,108 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// maxSubprocessLineSize bounds how much unterminated output SubprocessLogger
+// buffers before giving up on seeing a newline and flushing what it has, so
+// a child that never writes "\n" (or writes one enormous line) can't grow
+// the buffer without limit.
+const maxSubprocessLineSize = 1 << 20 // 1 MiB
+
+// SubprocessLogger is an io.Writer meant for exec.Cmd.Stdout/Stderr: it
+// splits the child's output into lines, tries to parse each as a JSON log
+// record ({level, ts, msg, ...}), and re-emits it through the parent Logger
+// tagged with component=, preserving any other fields as structured
+// context. A line that isn't JSON, or has no usable level, is logged at
+// Information.
+type SubprocessLogger struct {
+	logger    *Logger
+	component string
+	buf       []byte
+}
+
+// SubprocessWriter returns a SubprocessLogger that re-logs everything
+// written to it through l, tagged with component.
+func (l *Logger) SubprocessWriter(component string) *SubprocessLogger {
+	return &SubprocessLogger{logger: l, component: component}
+}
+
+// Write implements io.Writer. It never returns a short count or an error
+// for malformed input - a child process shouldn't see a write failure just
+// because its log line wasn't valid JSON.
+func (s *SubprocessLogger) Write(p []byte) (n int, err error) {
+	n = len(p)
+	s.buf = append(s.buf, p...)
+	for {
+		idx := bytes.IndexByte(s.buf, '\n')
+		if idx < 0 {
+			break
+		}
+		s.emit(s.buf[:idx])
+		s.buf = s.buf[idx+1:]
+	}
+	if len(s.buf) > maxSubprocessLineSize {
+		s.emit(s.buf)
+		s.buf = nil
+	}
+	return n, nil
+}
+
+// Close flushes any unterminated trailing partial line. Callers should call
+// it once the child's stdout/stderr has been fully drained, e.g. after
+// cmd.Wait().
+func (s *SubprocessLogger) Close() error {
+	if len(s.buf) > 0 {
+		s.emit(s.buf)
+		s.buf = nil
+	}
+	return nil
+}
+
+func (s *SubprocessLogger) emit(line []byte) {
+	line = bytes.TrimRight(line, "\r")
+	if len(line) == 0 {
+		return
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		s.logger.logAt(Information, string(line), 0, "component", s.component)
+		return
+	}
+
+	msg := string(line)
+	if raw, ok := fields["msg"]; ok {
+		json.Unmarshal(raw, &msg)
+		delete(fields, "msg")
+	}
+
+	var lev Level = Information
+	if raw, ok := fields["level"]; ok {
+		var levelStr string
+		if err := json.Unmarshal(raw, &levelStr); err == nil && strings.TrimSpace(levelStr) != "" {
+			if parsed, err := parseLevel(levelStr); err == nil {
+				lev = parsed
+			}
+		}
+		delete(fields, "level")
+	}
+	delete(fields, "ts")
+
+	kv := make([]any, 0, 2*len(fields)+2)
+	kv = append(kv, "component", s.component)
+	for k, raw := range fields {
+		var v any
+		if err := json.Unmarshal(raw, &v); err == nil {
+			kv = append(kv, k, v)
+		}
+	}
+
+	// logAt with skip 0: emit relays a line from a child process, so the
+	// call stack here - inside the log package itself - is never the
+	// record's real origin, unlike a direct Trace/Info/... call.
+	s.logger.logAt(lev, msg, 0, kv...)
+}
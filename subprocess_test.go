@@ -0,0 +1,116 @@
+package log
+
+import (
+	"sync"
+	"testing"
+)
+
+// recordingHandler collects every Record handed to it, for tests that need
+// to inspect what a Logger actually emitted.
+type recordingHandler struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+func (r *recordingHandler) Enabled(Level) bool { return true }
+
+func (r *recordingHandler) Handle(rec Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, rec)
+	return nil
+}
+
+func (r *recordingHandler) last() Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.records[len(r.records)-1]
+}
+
+// TestSubprocessLoggerFallsBackToInformation checks that a line which isn't
+// JSON, or is JSON with no usable "level" field, is still re-emitted - at
+// Information - rather than dropped.
+func TestSubprocessLoggerFallsBackToInformation(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"not json", "plain text output\n"},
+		{"json without level", `{"msg":"starting up"}` + "\n"},
+		{"json with unknown level", `{"msg":"starting up","level":"bogus"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &recordingHandler{}
+			logger := NewWithHandler(h)
+			sw := logger.SubprocessWriter("child")
+
+			if _, err := sw.Write([]byte(tt.line)); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if err := sw.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			rec := h.last()
+			if rec.Level != Information {
+				t.Errorf("Level = %v, want Information", rec.Level)
+			}
+		})
+	}
+}
+
+// TestSubprocessLoggerParsesLevel checks that a JSON line with a recognized
+// level is re-emitted at that level, with msg/level/ts stripped from the
+// fields and component attached.
+func TestSubprocessLoggerParsesLevel(t *testing.T) {
+	h := &recordingHandler{}
+	logger := NewWithHandler(h)
+	sw := logger.SubprocessWriter("child")
+
+	line := `{"level":"error","msg":"boom","ts":"2024-01-01T00:00:00Z","code":42}` + "\n"
+	if _, err := sw.Write([]byte(line)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	rec := h.last()
+	if rec.Level != ErrorL {
+		t.Errorf("Level = %v, want ErrorL", rec.Level)
+	}
+	if rec.Message != "boom" {
+		t.Errorf("Message = %q, want %q", rec.Message, "boom")
+	}
+
+	found := false
+	for i := 0; i+1 < len(rec.KV); i += 2 {
+		if rec.KV[i] == "component" && rec.KV[i+1] == "child" {
+			found = true
+		}
+		switch rec.KV[i] {
+		case "msg", "level", "ts":
+			t.Errorf("KV = %v, want %v stripped", rec.KV, rec.KV[i])
+		}
+	}
+	if !found {
+		t.Errorf("KV = %v, want component=child present", rec.KV)
+	}
+}
+
+// TestSubprocessLoggerDoesNotAttachSource checks that a relayed record's
+// Source is left empty rather than pointing at SubprocessLogger.emit's own
+// location - that location isn't where the log line actually came from, so
+// a misleading Source is worse than none.
+func TestSubprocessLoggerDoesNotAttachSource(t *testing.T) {
+	h := &recordingHandler{}
+	logger := NewWithHandler(h)
+	sw := logger.SubprocessWriter("child")
+
+	if _, err := sw.Write([]byte(`{"level":"info","msg":"hello"}` + "\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if rec := h.last(); rec.Source != "" {
+		t.Errorf("Source = %q, want empty", rec.Source)
+	}
+}
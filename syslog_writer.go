@@ -0,0 +1,48 @@
+//go:build !windows
+
+package log
+
+import (
+	"log/syslog"
+	"time"
+)
+
+// SyslogWriter is a LogWriter on top of the standard log/syslog client,
+// mapping our Level onto syslog severities.
+type SyslogWriter struct {
+	w *syslog.Writer
+}
+
+// NewSyslogWriter dials the syslog daemon at addr (or the local one if addr
+// is empty) over network ("" for the local unix socket, else "tcp"/"udp"),
+// tagging every message with tag under the given facility (one of the
+// syslog.LOG_* facility constants, e.g. syslog.LOG_USER). facility is typed
+// as a plain int rather than syslog.Priority so callers that need to build
+// on windows too - like LoadConfig - don't have to import log/syslog
+// themselves.
+func NewSyslogWriter(network, addr string, facility int, tag string) (LogWriter, error) {
+	w, err := syslog.Dial(network, addr, syslog.Priority(facility)|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogWriter{w: w}, nil
+}
+
+// WriteMsg maps level onto the nearest syslog severity and forwards msg.
+func (s *SyslogWriter) WriteMsg(t time.Time, level Level, msg string) error {
+	switch level {
+	case TraceL, DebugL:
+		return s.w.Debug(msg)
+	case Information:
+		return s.w.Info(msg)
+	case Warning:
+		return s.w.Warning(msg)
+	default:
+		return s.w.Err(msg)
+	}
+}
+
+// Close closes the underlying syslog connection.
+func (s *SyslogWriter) Close() error {
+	return s.w.Close()
+}
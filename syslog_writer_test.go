@@ -0,0 +1,81 @@
+//go:build !windows
+
+package log
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSyslogWriterMapsLevelToSeverity checks that each Level is forwarded
+// to the syslog daemon under the expected RFC 5424 severity, and that the
+// message text reaches the packet.
+func TestSyslogWriterMapsLevelToSeverity(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket: %v", err)
+	}
+	defer conn.Close()
+
+	w, err := NewSyslogWriter("udp", conn.LocalAddr().String(), defaultSyslogFacility, "logtest")
+	if err != nil {
+		t.Fatalf("NewSyslogWriter: %v", err)
+	}
+	defer w.Close()
+
+	tests := []struct {
+		level    Level
+		severity int
+	}{
+		{TraceL, 7},
+		{DebugL, 7},
+		{Information, 6},
+		{Warning, 4},
+		{ErrorL, 3},
+	}
+
+	for _, tt := range tests {
+		if err := w.WriteMsg(time.Now(), tt.level, "hello world"); err != nil {
+			t.Fatalf("WriteMsg(%v): %v", tt.level, err)
+		}
+
+		buf := make([]byte, 1024)
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("ReadFrom: %v", err)
+		}
+		packet := string(buf[:n])
+
+		pri, ok := parsePRI(packet)
+		if !ok {
+			t.Fatalf("packet %q has no <PRI> prefix", packet)
+		}
+		severity := pri % 8
+		if severity != tt.severity {
+			t.Errorf("level %v: severity = %d, want %d (packet %q)", tt.level, severity, tt.severity, packet)
+		}
+		if !strings.Contains(packet, "hello world") {
+			t.Errorf("packet %q missing message text", packet)
+		}
+	}
+}
+
+// parsePRI extracts the numeric value of a syslog packet's leading <PRI>.
+func parsePRI(packet string) (int, bool) {
+	if len(packet) == 0 || packet[0] != '<' {
+		return 0, false
+	}
+	end := strings.IndexByte(packet, '>')
+	if end < 1 {
+		return 0, false
+	}
+	pri, err := strconv.Atoi(packet[1:end])
+	if err != nil {
+		return 0, false
+	}
+	return pri, true
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package log
+
+import "fmt"
+
+// NewSyslogWriter always fails on Windows: log/syslog has no Windows
+// implementation. It exists so callers (like LoadConfig) can reference a
+// "syslog" filter type on any platform and get a clear error instead of a
+// build failure.
+func NewSyslogWriter(network, addr string, facility int, tag string) (LogWriter, error) {
+	return nil, fmt.Errorf("log: syslog sink is not supported on windows")
+}
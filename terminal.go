@@ -0,0 +1,214 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// terminalColors maps a Level to the ANSI escape code used when colorizing
+// its prefix. Kept intentionally small - this is meant to be read quickly in
+// a dev terminal, not to be configurable.
+var terminalColors = map[Level]string{
+	TraceL:      "\x1b[90m", // bright black
+	DebugL:      "\x1b[36m", // cyan
+	Information: "\x1b[32m", // green
+	Warning:     "\x1b[33m", // yellow
+	ErrorL:      "\x1b[31m", // red
+}
+
+const colorReset = "\x1b[0m"
+
+// carriageReturn ends a wrapped line inside reshape before the next one
+// starts; despite the name, reshape only ever needs a plain newline here.
+const carriageReturn = "\n"
+
+// TerminalHandler writes whatever its Formatter renders to an io.Writer,
+// taking its mutex only around the final Write so formatting never
+// serializes with other goroutines logging through this same handler.
+type TerminalHandler struct {
+	w         io.Writer
+	level     Level
+	formatter Formatter
+
+	mu sync.Mutex
+}
+
+// NewTerminalHandler returns a Handler writing to w with the historical
+// terminal preset: a timestamp/level prefix, optionally colorized, with the
+// message optionally reshaped to TextMaxWidth. For JSON, logfmt, or a custom pattern on
+// this same writer, use NewTerminalHandlerWithFormatter instead.
+func NewTerminalHandler(w io.Writer, level Level, reshape, color bool) *TerminalHandler {
+	return NewTerminalHandlerWithFormatter(w, level, TerminalFormatter{Reshape: reshape, Color: color})
+}
+
+// NewTerminalHandlerWithFormatter returns a Handler writing to w through an
+// arbitrary Formatter - TerminalFormatter, JSONFormatter, LogfmtFormatter,
+// or a PatternFormatter - so the stdout/file sinks built by New aren't
+// limited to the reshape-based preset.
+func NewTerminalHandlerWithFormatter(w io.Writer, level Level, formatter Formatter) *TerminalHandler {
+	return &TerminalHandler{w: w, level: level, formatter: formatter}
+}
+
+// Enabled reports whether level meets this handler's own threshold.
+func (t *TerminalHandler) Enabled(level Level) bool {
+	return level >= t.level
+}
+
+// Handle renders r through the handler's Formatter and writes the result.
+func (t *TerminalHandler) Handle(r Record) error {
+	b, err := t.formatter.Format(r)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, err = t.w.Write(b)
+	return err
+}
+
+// TerminalFormatter is the historical terminal rendering: a timestamp and
+// single-letter level prefix, optionally colorized, with the message
+// reshaped to align continuation lines under the prefix - now just one
+// Formatter among several rather than something TerminalHandler hardcodes.
+type TerminalFormatter struct {
+	Reshape bool
+	Color   bool
+}
+
+func (f TerminalFormatter) Format(r Record) ([]byte, error) {
+	p := prefix(r.Time, r.Level)
+	if f.Color {
+		p = colorize(r.Level, p)
+	}
+	msg := r.Message
+	if len(r.KV) > 0 {
+		msg += " " + formatKV(r.KV)
+	}
+
+	buf := getBuf()
+	defer putBuf(buf)
+	if f.Reshape {
+		buf.WriteString(reshape(p, msg))
+	} else {
+		buf.WriteString(p)
+		buf.WriteString(msg)
+	}
+	buf.WriteByte('\n')
+
+	out := make([]byte, buf.Len())
+	copy(out, buf.Bytes())
+	return out, nil
+}
+
+// colorize wraps a rendered prefix in the ANSI color for level.
+func colorize(level Level, s string) string {
+	c, ok := terminalColors[level]
+	if !ok {
+		return s
+	}
+	return c + s + colorReset
+}
+
+// formatKV renders kv as space separated key=value pairs, quoting any value
+// whose string form contains whitespace.
+func formatKV(kv []any) string {
+	var buf bytes.Buffer
+	for i := 0; i+1 < len(kv); i += 2 {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		fmt.Fprintf(&buf, "%v=%s", kv[i], quoteIfNeeded(fmt.Sprintf("%v", kv[i+1])))
+	}
+	return buf.String()
+}
+
+func quoteIfNeeded(s string) string {
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' {
+			return fmt.Sprintf("%q", s)
+		}
+	}
+	return s
+}
+
+func levelChar(level Level) string {
+	switch level {
+	case TraceL:
+		return "T"
+	case Information:
+		return "I"
+	case Warning:
+		return "W"
+	case DebugL:
+		return "D"
+	default:
+		return "E"
+	}
+}
+
+func prefix(t time.Time, level Level) string {
+	return fmt.Sprintf("%v [%v] -\t", t.Format("2006-01-02T15:04:05-0700"), levelChar(level))
+}
+
+// reshape attempts to answer the visual problem of giving a margin to text
+// based on the length of the desired prefix. This is so tha the eye level of
+// the logs are aligned without having to worry about having to sort through
+// the. Assumes ASCII
+func reshape(prefix, text string) string {
+	leftmargin := len(prefix)
+	var (
+		words = make([][]byte, 0, len(text))
+		_text = []byte(text)
+		word  = make([]byte, 0, 15)
+		buf   bytes.Buffer
+	)
+	for i, char := range _text {
+		if char == 0x20 || char == 0xA || char == 0xD {
+			if len(word) > 0 {
+				words = append(words, word)
+			}
+			word = make([]byte, 0, 15)
+			continue
+		}
+		word = append(word, char)
+		if i == len(_text)-1 {
+			words = append(words, word)
+		}
+	}
+
+	// Will likely not grow very often, so safe to give a small header
+	buf.Grow(len(text) + 50)
+
+	line := make([]byte, 0, 15)
+	initLine := func(linesIndex int) {
+		line = make([]byte, 0, 15)
+		if linesIndex == 0 {
+			return
+		}
+		for i := 0; i < leftmargin-4; i++ {
+			line = append(line, 0x20)
+		}
+		line = append(line, 0x9)
+	}
+	initLine(0)
+	line = []byte(prefix)
+	for i, word := range words {
+		if len(word)+len(line) > TextMaxWidth {
+			buf.Write(line)
+			buf.WriteString(carriageReturn)
+			initLine(i)
+		}
+		if len(line) > 0 {
+			line = append(line, 0x20)
+		}
+		line = append(line, word...)
+		if i == len(words)-1 {
+			buf.Write(line)
+		}
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,84 @@
+package log
+
+import (
+	"path"
+	"strings"
+	"sync"
+)
+
+// VmoduleHandler wraps another Handler and lets the verbosity threshold be
+// overridden per package, the way glog's -vmodule flag works: a record from
+// a package with no override falls back to the handler's default level.
+type VmoduleHandler struct {
+	next  Handler
+	level Level
+
+	mu      sync.RWMutex
+	modules map[string]Level
+}
+
+// NewVmoduleHandler returns a VmoduleHandler delegating to next. level is
+// the default threshold used for any package without an override.
+func NewVmoduleHandler(next Handler, level Level) *VmoduleHandler {
+	return &VmoduleHandler{next: next, level: level, modules: make(map[string]Level)}
+}
+
+// SetVmodule overrides the verbosity threshold for pkg, the import path
+// (or path suffix, e.g. "myapp/internal/worker") derived from the caller's
+// source file.
+func (v *VmoduleHandler) SetVmodule(pkg string, level Level) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.modules[pkg] = level
+}
+
+// Enabled reports whether level meets the handler's default threshold. This
+// is necessarily coarse - the actual per-package decision needs the record's
+// Source and is made in Handle.
+func (v *VmoduleHandler) Enabled(level Level) bool {
+	v.mu.RLock()
+	hasOverrides := len(v.modules) > 0
+	v.mu.RUnlock()
+	if hasOverrides {
+		return true
+	}
+	return level >= v.level && v.next.Enabled(level)
+}
+
+// Handle drops r if its level is below the threshold configured for its
+// source package, then forwards it to next.
+func (v *VmoduleHandler) Handle(r Record) error {
+	threshold := v.level
+	if lvl, ok := v.levelFor(r.Source); ok {
+		threshold = lvl
+	}
+	if r.Level < threshold {
+		return nil
+	}
+	return v.next.Handle(r)
+}
+
+func (v *VmoduleHandler) levelFor(source string) (Level, bool) {
+	if source == "" {
+		return 0, false
+	}
+	pkg := packageOf(source)
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	for p, lvl := range v.modules {
+		if pkg == p || strings.HasSuffix(pkg, "/"+p) {
+			return lvl, true
+		}
+	}
+	return 0, false
+}
+
+// packageOf extracts the directory portion of a "file:line" source string,
+// which for a well-formed Go source tree is the package's import path
+// suffix.
+func packageOf(source string) string {
+	if idx := strings.LastIndex(source, ":"); idx > 0 {
+		source = source[:idx]
+	}
+	return path.Dir(source)
+}
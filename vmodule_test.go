@@ -0,0 +1,30 @@
+package log
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestVmoduleHandlerConcurrentSetAndEnabled exercises SetVmodule and
+// Enabled from separate goroutines at once - under -race this catches a
+// concurrent map read/write if Enabled's fast path ever reads v.modules
+// without v.mu held.
+func TestVmoduleHandlerConcurrentSetAndEnabled(t *testing.T) {
+	v := NewVmoduleHandler(&recordingHandler{}, Information)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			v.SetVmodule("pkg", TraceL)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			v.Enabled(TraceL)
+		}
+	}()
+	wg.Wait()
+}